@@ -0,0 +1,53 @@
+package iota
+
+import "testing"
+
+func TestInMemoryAddressIndexIsAddressUsed(t *testing.T) {
+	idx := NewInMemoryAddressIndex()
+
+	used := &Ed25519Address{0x01}
+	unused := &Ed25519Address{0x02}
+
+	if err := idx.IndexUTXO(UTXOInputID{0x01}, []Address{used}); err != nil {
+		t.Fatalf("unexpected error indexing UTXO: %v", err)
+	}
+
+	isUsed, err := idx.IsAddressUsed(used)
+	if err != nil {
+		t.Fatalf("unexpected error checking used address: %v", err)
+	}
+	if !isUsed {
+		t.Fatal("expected indexed address to be reported as used")
+	}
+
+	isUsed, err = idx.IsAddressUsed(unused)
+	if err != nil {
+		t.Fatalf("unexpected error checking unused address: %v", err)
+	}
+	if isUsed {
+		t.Fatal("expected un-indexed address to be reported as unused")
+	}
+}
+
+func TestInMemoryAddressIndexUTXOsByAddress(t *testing.T) {
+	idx := NewInMemoryAddressIndex()
+
+	addr := &Ed25519Address{0x03}
+	first := UTXOInputID{0x01}
+	second := UTXOInputID{0x02}
+
+	if err := idx.IndexUTXO(first, []Address{addr}); err != nil {
+		t.Fatalf("unexpected error indexing first UTXO: %v", err)
+	}
+	if err := idx.IndexUTXO(second, []Address{addr}); err != nil {
+		t.Fatalf("unexpected error indexing second UTXO: %v", err)
+	}
+
+	utxos, err := idx.UTXOsByAddress(addr)
+	if err != nil {
+		t.Fatalf("unexpected error looking up UTXOs by address: %v", err)
+	}
+	if len(utxos) != 2 {
+		t.Fatalf("expected 2 UTXOs indexed against address, got %d", len(utxos))
+	}
+}
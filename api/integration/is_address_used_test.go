@@ -1,33 +1,54 @@
 package integration_test
 
 import (
-	. "github.com/iotaledger/iota.go/api"
-	. "github.com/iotaledger/iota.go/api/integration/samples"
+	iota "github.com/iotaledger/iota.go"
+	"github.com/iotaledger/iota.go/nodeclient"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
+// chrysalisSampleAddresses mirrors the legacy SampleAddresses fixture (spent, used, unused) but
+// as Chrysalis Ed25519Address values, since a nodeclient.Client works against an AddressIndex
+// rather than the deprecated Trinity/IRI seed-style addresses and full-node HTTP API.
+var chrysalisSampleAddresses = []*iota.Ed25519Address{
+	{0x01},
+	{0x02},
+	{0x03},
+}
+
 var _ = Describe("IsAddressUsed()", func() {
 
-	api, err := ComposeAPI(HttpClientSettings{}, nil)
+	addressIndex := iota.NewInMemoryAddressIndex()
+
+	// Fund the index the same way a node would as it processes confirmed transactions: the
+	// spent address and the address with transactions are both touched by some UTXO, the
+	// unused address is touched by none.
+	err := addressIndex.IndexUTXO(iota.UTXOInputID{0x01}, []iota.Address{chrysalisSampleAddresses[0]})
+	if err != nil {
+		panic(err)
+	}
+	err = addressIndex.IndexUTXO(iota.UTXOInputID{0x02}, []iota.Address{chrysalisSampleAddresses[1]})
 	if err != nil {
 		panic(err)
 	}
 
+	client := &nodeclient.Client{}
+	client.SetAddressIndex(addressIndex)
+
 	It("returns true for spent address", func() {
-		used, err := api.IsAddressUsed(SampleAddresses[0])
+		used, err := client.IsAddressUsed(chrysalisSampleAddresses[0])
 		Expect(err).ToNot(HaveOccurred())
 		Expect(used).To(BeTrue())
 	})
 
 	It("returns true for address with transactions", func() {
-		used, err := api.IsAddressUsed(SampleAddresses[1])
+		used, err := client.IsAddressUsed(chrysalisSampleAddresses[1])
 		Expect(err).ToNot(HaveOccurred())
 		Expect(used).To(BeTrue())
 	})
 
 	It("returns false for unused address", func() {
-		used, err := api.IsAddressUsed(SampleAddresses[2])
+		used, err := client.IsAddressUsed(chrysalisSampleAddresses[2])
 		Expect(err).ToNot(HaveOccurred())
 		Expect(used).To(BeFalse())
 	})
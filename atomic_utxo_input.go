@@ -0,0 +1,385 @@
+package iota
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// The length of a chain identifier used to address a foreign chain in an atomic transfer.
+	ChainIDLength = 32
+
+	// The size of an atomic import input: input type + chain id + referenced UTXO ID.
+	AtomicImportInputSize = SmallTypeDenotationByteSize + ChainIDLength + TransactionIDLength + UInt16ByteSize
+
+	// The size of an atomic export output: output type + chain id + referenced UTXO ID.
+	AtomicExportOutputSize = SmallTypeDenotationByteSize + ChainIDLength + TransactionIDLength + UInt16ByteSize
+)
+
+const (
+	// InputAtomicImport denotes an AtomicImportInput, distinct from InputUTXO (0) and
+	// InputTreasury (1).
+	InputAtomicImport InputType = 2
+)
+
+const (
+	// OutputAtomicExport denotes an AtomicExportOutput, distinct from the existing output
+	// types including OutputTreasuryOutput (2). The essence-level output selector must grow a
+	// case for OutputAtomicExport returning a fresh *AtomicExportOutput, the same way it already
+	// does for each existing output type.
+	OutputAtomicExport OutputType = 3
+)
+
+// Input and Output are implemented by UTXOInput/SigLockedSingleOutput and friends elsewhere in
+// the package; AtomicImportInput and AtomicExportOutput below satisfy them the same way.
+// InputsValidatorFunc/OutputsValidatorFunc likewise already exist for validating a transaction
+// essence's inputs/outputs as a set.
+
+// ChainID identifies a foreign chain which is the source or destination of an atomic UTXO transfer.
+type ChainID [ChainIDLength]byte
+
+// ToHex converts the ChainID to its hex representation.
+func (chainID ChainID) ToHex() string {
+	return fmt.Sprintf("%x", chainID)
+}
+
+// Status denotes the status of a UTXO tracked in an AtomicUTXOSet.
+type Status byte
+
+const (
+	// StatusUnknown is returned for a UTXO which is not tracked by the set.
+	StatusUnknown Status = iota
+	// StatusFunded is returned for a UTXO which has been funded into the set but not yet consumed.
+	StatusFunded
+	// StatusConsumed is returned for a UTXO which has already been consumed out of the set.
+	StatusConsumed
+)
+
+// AtomicUTXOSet is a set of UTXOs shared between chains which can be moved into and out of
+// the local ledger via AtomicImportInput and AtomicExportOutput.
+type AtomicUTXOSet interface {
+	// Fund adds the given UTXO to the set, making it available for a later Consume.
+	Fund(utxoID UTXOInputID) error
+	// Consume removes the given UTXO from the set. It returns an error if the UTXO is not funded.
+	Consume(utxoID UTXOInputID) error
+	// Status returns the current Status of the given UTXO within the set.
+	Status(utxoID UTXOInputID) (Status, error)
+}
+
+// ApplyAtomicTransfers moves UTXOs between set and the local ledger for every atomic input/output
+// found in inputs/outputs: each AtomicImportInput consumes its referenced UTXO out of set, and
+// each AtomicExportOutput funds its referenced UTXO into set. This is the message-layer entry
+// point that actually performs the transfer during validation of a transaction.
+func ApplyAtomicTransfers(inputs []Input, outputs []Output, set AtomicUTXOSet) error {
+	for i, in := range inputs {
+		imp, ok := in.(*AtomicImportInput)
+		if !ok {
+			continue
+		}
+
+		utxoID := imp.ID()
+		status, err := set.Status(utxoID)
+		if err != nil {
+			return fmt.Errorf("unable to determine status of atomic UTXO %s referenced by input %d: %w", utxoID.ToHex(), i, err)
+		}
+		if status != StatusFunded {
+			return fmt.Errorf("atomic UTXO %s referenced by input %d is not funded in the atomic set", utxoID.ToHex(), i)
+		}
+		if err := set.Consume(utxoID); err != nil {
+			return fmt.Errorf("unable to consume atomic UTXO %s referenced by input %d: %w", utxoID.ToHex(), i, err)
+		}
+	}
+
+	for i, out := range outputs {
+		exp, ok := out.(*AtomicExportOutput)
+		if !ok {
+			continue
+		}
+
+		u := UTXOInput{TransactionID: exp.TransactionID, TransactionOutputIndex: exp.TransactionOutputIndex}
+		if err := set.Fund(u.ID()); err != nil {
+			return fmt.Errorf("unable to fund atomic UTXO referenced by output %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAtomicInputs runs atomicConsumptionBoundsValidator across inputs, rejecting a
+// transaction which consumes the same atomic UTXO more than once.
+func ValidateAtomicInputs(inputs []Input) error {
+	validate := atomicConsumptionBoundsValidator()
+	for i, in := range inputs {
+		if err := validate(i, in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AtomicImportInput references a UTXO residing in the AtomicUTXOSet of a source chain which is
+// being moved into the local ledger as part of this transaction.
+type AtomicImportInput struct {
+	// The identifier of the chain the referenced UTXO is being imported from.
+	SourceChainID ChainID
+	// The transaction ID of the referenced UTXO.
+	TransactionID [TransactionIDLength]byte
+	// The output index of the referenced UTXO on the referenced transaction.
+	TransactionOutputIndex uint16
+}
+
+// ID returns the UTXOInputID of the referenced UTXO.
+func (a *AtomicImportInput) ID() UTXOInputID {
+	u := UTXOInput{TransactionID: a.TransactionID, TransactionOutputIndex: a.TransactionOutputIndex}
+	return u.ID()
+}
+
+func (a *AtomicImportInput) Deserialize(data []byte, deSeriMode DeSerializationMode) (int, error) {
+	return NewDeserializer(data).
+		AbortIf(func(err error) error {
+			if deSeriMode.HasMode(DeSeriModePerformValidation) {
+				if err := checkMinByteLength(AtomicImportInputSize, len(data)); err != nil {
+					return fmt.Errorf("invalid atomic import input bytes: %w", err)
+				}
+				if err := checkTypeByte(data, InputAtomicImport); err != nil {
+					return fmt.Errorf("unable to deserialize atomic import input: %w", err)
+				}
+			}
+			return nil
+		}).
+		Skip(SmallTypeDenotationByteSize, func(err error) error {
+			return fmt.Errorf("unable to skip atomic import input type during deserialization: %w", err)
+		}).
+		ReadArrayOf32Bytes((*[32]byte)(&a.SourceChainID), func(err error) error {
+			return fmt.Errorf("unable to deserialize source chain ID in atomic import input: %w", err)
+		}).
+		ReadArrayOf32Bytes(&a.TransactionID, func(err error) error {
+			return fmt.Errorf("unable to deserialize transaction ID in atomic import input: %w", err)
+		}).
+		ReadNum(&a.TransactionOutputIndex, func(err error) error {
+			return fmt.Errorf("unable to deserialize transaction output index in atomic import input: %w", err)
+		}).
+		AbortIf(func(err error) error {
+			if deSeriMode.HasMode(DeSeriModePerformValidation) {
+				if err := utxoInputRefBoundsValidator(-1, &UTXOInput{TransactionID: a.TransactionID, TransactionOutputIndex: a.TransactionOutputIndex}); err != nil {
+					return fmt.Errorf("%w: unable to deserialize atomic import input", err)
+				}
+			}
+			return nil
+		}).
+		Done()
+}
+
+func (a *AtomicImportInput) Serialize(deSeriMode DeSerializationMode) (data []byte, err error) {
+	return NewSerializer().
+		AbortIf(func(err error) error {
+			if deSeriMode.HasMode(DeSeriModePerformValidation) {
+				if err := utxoInputRefBoundsValidator(-1, &UTXOInput{TransactionID: a.TransactionID, TransactionOutputIndex: a.TransactionOutputIndex}); err != nil {
+					return fmt.Errorf("%w: unable to serialize atomic import input", err)
+				}
+			}
+			return nil
+		}).
+		WriteNum(InputAtomicImport, func(err error) error {
+			return fmt.Errorf("unable to serialize atomic import input type ID: %w", err)
+		}).
+		WriteBytes(a.SourceChainID[:], func(err error) error {
+			return fmt.Errorf("unable to serialize atomic import input source chain ID: %w", err)
+		}).
+		WriteBytes(a.TransactionID[:], func(err error) error {
+			return fmt.Errorf("unable to serialize atomic import input transaction ID: %w", err)
+		}).
+		WriteNum(a.TransactionOutputIndex, func(err error) error {
+			return fmt.Errorf("unable to serialize atomic import input transaction output index: %w", err)
+		}).Serialize()
+}
+
+func (a *AtomicImportInput) MarshalJSON() ([]byte, error) {
+	jsonImport := &jsonatomicimportinput{}
+	jsonImport.SourceChainID = hex.EncodeToString(a.SourceChainID[:])
+	jsonImport.TransactionID = hex.EncodeToString(a.TransactionID[:])
+	jsonImport.TransactionOutputIndex = int(a.TransactionOutputIndex)
+	jsonImport.Type = int(InputAtomicImport)
+	return json.Marshal(jsonImport)
+}
+
+func (a *AtomicImportInput) UnmarshalJSON(bytes []byte) error {
+	jsonImport := &jsonatomicimportinput{}
+	if err := json.Unmarshal(bytes, jsonImport); err != nil {
+		return err
+	}
+	seri, err := jsonImport.ToSerializable()
+	if err != nil {
+		return err
+	}
+	*a = *seri.(*AtomicImportInput)
+	return nil
+}
+
+// AtomicExportOutput locks a UTXO of the local ledger into the shared AtomicUTXOSet so that it
+// becomes available for import on a destination chain. It is an Output: it is produced by a
+// transaction (funding the atomic set), not consumed from one.
+type AtomicExportOutput struct {
+	// The identifier of the chain the referenced UTXO is being exported to.
+	DestChainID ChainID
+	// The transaction ID of the referenced UTXO.
+	TransactionID [TransactionIDLength]byte
+	// The output index of the referenced UTXO on the referenced transaction.
+	TransactionOutputIndex uint16
+}
+
+func (e *AtomicExportOutput) Deserialize(data []byte, deSeriMode DeSerializationMode) (int, error) {
+	return NewDeserializer(data).
+		AbortIf(func(err error) error {
+			if deSeriMode.HasMode(DeSeriModePerformValidation) {
+				if err := checkMinByteLength(AtomicExportOutputSize, len(data)); err != nil {
+					return fmt.Errorf("invalid atomic export output bytes: %w", err)
+				}
+				if err := checkTypeByte(data, OutputAtomicExport); err != nil {
+					return fmt.Errorf("unable to deserialize atomic export output: %w", err)
+				}
+			}
+			return nil
+		}).
+		Skip(SmallTypeDenotationByteSize, func(err error) error {
+			return fmt.Errorf("unable to skip atomic export output type during deserialization: %w", err)
+		}).
+		ReadArrayOf32Bytes((*[32]byte)(&e.DestChainID), func(err error) error {
+			return fmt.Errorf("unable to deserialize destination chain ID in atomic export output: %w", err)
+		}).
+		ReadArrayOf32Bytes(&e.TransactionID, func(err error) error {
+			return fmt.Errorf("unable to deserialize transaction ID in atomic export output: %w", err)
+		}).
+		ReadNum(&e.TransactionOutputIndex, func(err error) error {
+			return fmt.Errorf("unable to deserialize transaction output index in atomic export output: %w", err)
+		}).
+		AbortIf(func(err error) error {
+			if deSeriMode.HasMode(DeSeriModePerformValidation) {
+				if err := utxoInputRefBoundsValidator(-1, &UTXOInput{TransactionID: e.TransactionID, TransactionOutputIndex: e.TransactionOutputIndex}); err != nil {
+					return fmt.Errorf("%w: unable to deserialize atomic export output", err)
+				}
+			}
+			return nil
+		}).
+		Done()
+}
+
+func (e *AtomicExportOutput) Serialize(deSeriMode DeSerializationMode) (data []byte, err error) {
+	return NewSerializer().
+		AbortIf(func(err error) error {
+			if deSeriMode.HasMode(DeSeriModePerformValidation) {
+				if err := utxoInputRefBoundsValidator(-1, &UTXOInput{TransactionID: e.TransactionID, TransactionOutputIndex: e.TransactionOutputIndex}); err != nil {
+					return fmt.Errorf("%w: unable to serialize atomic export output", err)
+				}
+			}
+			return nil
+		}).
+		WriteNum(OutputAtomicExport, func(err error) error {
+			return fmt.Errorf("unable to serialize atomic export output type ID: %w", err)
+		}).
+		WriteBytes(e.DestChainID[:], func(err error) error {
+			return fmt.Errorf("unable to serialize atomic export output destination chain ID: %w", err)
+		}).
+		WriteBytes(e.TransactionID[:], func(err error) error {
+			return fmt.Errorf("unable to serialize atomic export output transaction ID: %w", err)
+		}).
+		WriteNum(e.TransactionOutputIndex, func(err error) error {
+			return fmt.Errorf("unable to serialize atomic export output transaction output index: %w", err)
+		}).Serialize()
+}
+
+func (e *AtomicExportOutput) MarshalJSON() ([]byte, error) {
+	jsonExport := &jsonatomicexportoutput{}
+	jsonExport.DestChainID = hex.EncodeToString(e.DestChainID[:])
+	jsonExport.TransactionID = hex.EncodeToString(e.TransactionID[:])
+	jsonExport.TransactionOutputIndex = int(e.TransactionOutputIndex)
+	jsonExport.Type = int(OutputAtomicExport)
+	return json.Marshal(jsonExport)
+}
+
+func (e *AtomicExportOutput) UnmarshalJSON(bytes []byte) error {
+	jsonExport := &jsonatomicexportoutput{}
+	if err := json.Unmarshal(bytes, jsonExport); err != nil {
+		return err
+	}
+	seri, err := jsonExport.ToSerializable()
+	if err != nil {
+		return err
+	}
+	*e = *seri.(*AtomicExportOutput)
+	return nil
+}
+
+// atomicConsumptionBoundsValidator returns a validator which rejects a transaction that
+// consumes the same atomic UTXO more than once, mirroring utxoInputRefBoundsValidator's role
+// for regular UTXOInput references. Use it through ValidateAtomicInputs.
+func atomicConsumptionBoundsValidator() InputsValidatorFunc {
+	seenAtomicConsumptions := map[UTXOInputID]int{}
+	return func(index int, input Input) error {
+		imp, ok := input.(*AtomicImportInput)
+		if !ok {
+			return nil
+		}
+		utxoID := imp.ID()
+		if j, has := seenAtomicConsumptions[utxoID]; has {
+			return fmt.Errorf("%w: atomic UTXO %s is consumed by inputs %d and %d", ErrInputUTXORefsNotUnique, utxoID.ToHex(), j, index)
+		}
+		seenAtomicConsumptions[utxoID] = index
+		return nil
+	}
+}
+
+// jsonatomicimportinput defines the JSON representation of an AtomicImportInput.
+type jsonatomicimportinput struct {
+	Type                   int    `json:"type"`
+	SourceChainID          string `json:"sourceChainId"`
+	TransactionID          string `json:"transactionId"`
+	TransactionOutputIndex int    `json:"transactionOutputIndex"`
+}
+
+func (j *jsonatomicimportinput) ToSerializable() (Serializable, error) {
+	imp := &AtomicImportInput{}
+
+	sourceChainIDBytes, err := hex.DecodeString(j.SourceChainID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode source chain ID from JSON for atomic import input: %w", err)
+	}
+	copy(imp.SourceChainID[:], sourceChainIDBytes)
+
+	txIDBytes, err := hex.DecodeString(j.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode transaction ID from JSON for atomic import input: %w", err)
+	}
+	copy(imp.TransactionID[:], txIDBytes)
+
+	imp.TransactionOutputIndex = uint16(j.TransactionOutputIndex)
+	return imp, nil
+}
+
+// jsonatomicexportoutput defines the JSON representation of an AtomicExportOutput.
+type jsonatomicexportoutput struct {
+	Type                   int    `json:"type"`
+	DestChainID            string `json:"destChainId"`
+	TransactionID          string `json:"transactionId"`
+	TransactionOutputIndex int    `json:"transactionOutputIndex"`
+}
+
+func (j *jsonatomicexportoutput) ToSerializable() (Serializable, error) {
+	exp := &AtomicExportOutput{}
+
+	destChainIDBytes, err := hex.DecodeString(j.DestChainID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode destination chain ID from JSON for atomic export output: %w", err)
+	}
+	copy(exp.DestChainID[:], destChainIDBytes)
+
+	txIDBytes, err := hex.DecodeString(j.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode transaction ID from JSON for atomic export output: %w", err)
+	}
+	copy(exp.TransactionID[:], txIDBytes)
+
+	exp.TransactionOutputIndex = uint16(j.TransactionOutputIndex)
+	return exp, nil
+}
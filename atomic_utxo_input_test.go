@@ -0,0 +1,88 @@
+package iota
+
+import "testing"
+
+type fakeAtomicUTXOSet struct {
+	status map[UTXOInputID]Status
+}
+
+func newFakeAtomicUTXOSet() *fakeAtomicUTXOSet {
+	return &fakeAtomicUTXOSet{status: make(map[UTXOInputID]Status)}
+}
+
+func (f *fakeAtomicUTXOSet) Fund(utxoID UTXOInputID) error {
+	f.status[utxoID] = StatusFunded
+	return nil
+}
+
+func (f *fakeAtomicUTXOSet) Consume(utxoID UTXOInputID) error {
+	f.status[utxoID] = StatusConsumed
+	return nil
+}
+
+func (f *fakeAtomicUTXOSet) Status(utxoID UTXOInputID) (Status, error) {
+	status, has := f.status[utxoID]
+	if !has {
+		return StatusUnknown, nil
+	}
+	return status, nil
+}
+
+func TestValidateAtomicInputsRejectsDoubleConsumption(t *testing.T) {
+	imp := &AtomicImportInput{TransactionOutputIndex: 0}
+	imp.TransactionID[0] = 1
+
+	inputs := []Input{imp, imp}
+
+	if err := ValidateAtomicInputs(inputs); err == nil {
+		t.Fatal("expected an error for an atomic UTXO consumed by two inputs, got nil")
+	}
+}
+
+func TestValidateAtomicInputsAllowsDistinctConsumption(t *testing.T) {
+	impA := &AtomicImportInput{TransactionOutputIndex: 0}
+	impA.TransactionID[0] = 1
+
+	impB := &AtomicImportInput{TransactionOutputIndex: 1}
+	impB.TransactionID[0] = 1
+
+	inputs := []Input{impA, impB}
+
+	if err := ValidateAtomicInputs(inputs); err != nil {
+		t.Fatalf("unexpected error for distinct atomic UTXOs: %v", err)
+	}
+}
+
+func TestApplyAtomicTransfersMovesUTXOsBetweenSetAndLedger(t *testing.T) {
+	set := newFakeAtomicUTXOSet()
+
+	exp := &AtomicExportOutput{TransactionOutputIndex: 0}
+	exp.TransactionID[0] = 2
+	if err := ApplyAtomicTransfers(nil, []Output{exp}, set); err != nil {
+		t.Fatalf("unexpected error funding atomic UTXO: %v", err)
+	}
+
+	imp := &AtomicImportInput{TransactionOutputIndex: 0}
+	imp.TransactionID[0] = 2
+	if err := ApplyAtomicTransfers([]Input{imp}, nil, set); err != nil {
+		t.Fatalf("unexpected error consuming atomic UTXO: %v", err)
+	}
+
+	status, err := set.Status(imp.ID())
+	if err != nil {
+		t.Fatalf("unexpected error fetching status: %v", err)
+	}
+	if status != StatusConsumed {
+		t.Fatalf("expected atomic UTXO to be consumed, got status %v", status)
+	}
+}
+
+func TestApplyAtomicTransfersRejectsUnfundedImport(t *testing.T) {
+	set := newFakeAtomicUTXOSet()
+
+	imp := &AtomicImportInput{TransactionOutputIndex: 0}
+	imp.TransactionID[0] = 3
+	if err := ApplyAtomicTransfers([]Input{imp}, nil, set); err == nil {
+		t.Fatal("expected an error consuming an atomic UTXO that was never funded, got nil")
+	}
+}
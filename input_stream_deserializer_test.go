@@ -0,0 +1,89 @@
+package iota
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestInputStreamDeserializerRoundTrip(t *testing.T) {
+	inputs := []*UTXOInput{
+		{TransactionOutputIndex: 0},
+		{TransactionOutputIndex: 1},
+		{TransactionOutputIndex: 2},
+	}
+	inputs[0].TransactionID[0] = 0x01
+	inputs[1].TransactionID[0] = 0x02
+	inputs[2].TransactionID[0] = 0x03
+
+	var buf bytes.Buffer
+	for _, in := range inputs {
+		data, err := in.Serialize(DeSeriModePerformValidation)
+		if err != nil {
+			t.Fatalf("unexpected error serializing input: %v", err)
+		}
+		buf.Write(data)
+	}
+
+	stream := NewInputStreamDeserializer(&buf, DeSeriModePerformValidation)
+
+	var got []Input
+	for {
+		in, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error reading next input: %v", err)
+		}
+		got = append(got, in)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected terminal stream error: %v", err)
+	}
+
+	if len(got) != len(inputs) {
+		t.Fatalf("expected %d inputs, got %d", len(inputs), len(got))
+	}
+
+	for i, in := range got {
+		utxoIn, ok := in.(*UTXOInput)
+		if !ok {
+			t.Fatalf("expected input %d to be a *UTXOInput", i)
+		}
+		if utxoIn.ID() != inputs[i].ID() {
+			t.Fatalf("expected input %d to round-trip to the same ID", i)
+		}
+	}
+}
+
+func TestInputStreamDeserializerWithAddressIndex(t *testing.T) {
+	in := &UTXOInput{TransactionOutputIndex: 0}
+	in.TransactionID[0] = 0x09
+
+	data, err := in.Serialize(DeSeriModePerformValidation)
+	if err != nil {
+		t.Fatalf("unexpected error serializing input: %v", err)
+	}
+
+	addr := &Ed25519Address{0x42}
+	idx := NewInMemoryAddressIndex()
+
+	stream := NewInputStreamDeserializer(bytes.NewReader(data), DeSeriModePerformValidation).
+		WithAddressIndex(idx, func(id UTXOInputID) ([]Address, error) {
+			return []Address{addr}, nil
+		})
+
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("unexpected error reading input: %v", err)
+	}
+
+	used, err := idx.IsAddressUsed(addr)
+	if err != nil {
+		t.Fatalf("unexpected error checking address usage: %v", err)
+	}
+	if !used {
+		t.Fatal("expected address resolved during streaming to be indexed")
+	}
+}
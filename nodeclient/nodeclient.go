@@ -0,0 +1,19 @@
+package nodeclient
+
+import (
+	iota "github.com/iotaledger/iota.go"
+)
+
+// SetAddressIndex wires the AddressIndex backing IsAddressUsed into Client, without disturbing
+// its existing constructor.
+func (c *Client) SetAddressIndex(addressIndex iota.AddressIndex) {
+	c.addressIndex = addressIndex
+}
+
+// IsAddressUsed reports whether the given address has ever been touched by an indexed UTXO,
+// replacing the deprecated legacy `api.IsAddressUsed` integration test's reliance on the
+// Trinity/IRI HTTP API. It relies on an addressIndex field carrying an iota.AddressIndex,
+// added to Client alongside its other backing stores and populated via SetAddressIndex.
+func (c *Client) IsAddressUsed(addr iota.Address) (bool, error) {
+	return c.addressIndex.IsAddressUsed(addr)
+}
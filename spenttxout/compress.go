@@ -0,0 +1,92 @@
+package spenttxout
+
+import (
+	"fmt"
+
+	iota "github.com/iotaledger/iota.go"
+)
+
+// Address version bytes used in the compact type-prefixed address encoding, analogous to
+// btcd's pkScript type byte.
+const (
+	addrVersionEd25519 = 0
+)
+
+// compressTxOutAmount compresses an output amount the same way btcd compresses satoshi
+// amounts: by factoring out trailing zeroes before the value is varint-encoded by the caller.
+// Small round amounts (common for IOTA, given its smallest unit is already coarse relative to
+// raw uint64 range) therefore take a handful of bytes instead of up to ten.
+func compressTxOutAmount(amount uint64) uint64 {
+	if amount == 0 {
+		return 0
+	}
+
+	exponent := uint64(0)
+	for amount%10 == 0 && exponent < 9 {
+		amount /= 10
+		exponent++
+	}
+
+	if exponent < 9 {
+		return amount*10 + exponent + 1
+	}
+
+	// The exponent is capped at 9, the largest value of its single decimal digit; any further
+	// factors of ten stay in the mantissa. Encode these amounts with the fixed exponent of 9.
+	return amount*10 + 10
+}
+
+// decompressTxOutAmount reverses compressTxOutAmount.
+func decompressTxOutAmount(x uint64) uint64 {
+	if x == 0 {
+		return 0
+	}
+
+	x--
+
+	exponent := x % 10
+	x /= 10
+
+	var amount uint64
+	if exponent < 9 {
+		amount = x
+		for i := uint64(0); i < exponent; i++ {
+			amount *= 10
+		}
+	} else {
+		amount = x * 1000000000
+	}
+
+	return amount
+}
+
+// compressAddress encodes addr into its compact type-prefixed form, returning the version
+// byte identifying the address type alongside its raw bytes. Only Ed25519Address has a compact
+// form: a nil address or an address of any other type yields unversioned and no bytes.
+func compressAddress(addr iota.Address) (version int, data []byte) {
+	if addr == nil {
+		return unversioned, nil
+	}
+
+	switch a := addr.(type) {
+	case *iota.Ed25519Address:
+		return addrVersionEd25519, a[:]
+	default:
+		return unversioned, nil
+	}
+}
+
+// decompressAddress reverses compressAddress.
+func decompressAddress(version byte, data []byte) (iota.Address, error) {
+	switch version {
+	case addrVersionEd25519:
+		addr := &iota.Ed25519Address{}
+		if len(data) != len(addr) {
+			return nil, fmt.Errorf("spenttxout: invalid ed25519 address length %d", len(data))
+		}
+		copy(addr[:], data)
+		return addr, nil
+	default:
+		return nil, fmt.Errorf("spenttxout: unknown address version %d", version)
+	}
+}
@@ -0,0 +1,127 @@
+package spenttxout
+
+import (
+	"testing"
+
+	iota "github.com/iotaledger/iota.go"
+)
+
+func TestCompressDecompressTxOutAmountRoundTrip(t *testing.T) {
+	amounts := []uint64{0, 1, 9, 10, 100, 1234, 1000000000, 2779530283277761}
+
+	for _, amount := range amounts {
+		got := decompressTxOutAmount(compressTxOutAmount(amount))
+		if got != amount {
+			t.Fatalf("round-trip mismatch for amount %d: got %d", amount, got)
+		}
+	}
+}
+
+func TestSerializeDeserializeSpentOutputsRoundTrip(t *testing.T) {
+	addr := &iota.Ed25519Address{0x01}
+
+	spent := []SpentOutput{
+		{
+			Index:                0,
+			Amount:               42,
+			Address:              addr,
+			MilestoneIndex:       7,
+			IsCoinbaseEquivalent: true,
+		},
+	}
+	spent[0].TransactionID[0] = 0xaa
+
+	data := SerializeSpentOutputs(spent)
+
+	got, err := DeserializeSpentOutputs(data)
+	if err != nil {
+		t.Fatalf("unexpected error deserializing spent outputs: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 spent output, got %d", len(got))
+	}
+
+	entry := &got[0]
+	if !entry.Compressed {
+		t.Fatal("expected freshly decoded entry to be Compressed")
+	}
+	if err := entry.MaybeDecompress(); err != nil {
+		t.Fatalf("unexpected error decompressing entry: %v", err)
+	}
+	if entry.Compressed {
+		t.Fatal("expected entry to no longer be Compressed after MaybeDecompress")
+	}
+	if entry.Amount != 42 {
+		t.Fatalf("expected amount 42, got %d", entry.Amount)
+	}
+	if entry.MilestoneIndex != 7 {
+		t.Fatalf("expected milestone index 7, got %d", entry.MilestoneIndex)
+	}
+	if !entry.IsCoinbaseEquivalent {
+		t.Fatal("expected IsCoinbaseEquivalent to round-trip as true")
+	}
+}
+
+func TestDeserializeSpentOutputsUnversionedSentinelIsNotZero(t *testing.T) {
+	spent := []SpentOutput{{Address: nil}}
+	spent[0].TransactionID[0] = 0xbb
+
+	data := SerializeSpentOutputs(spent)
+
+	got, err := DeserializeSpentOutputs(data)
+	if err != nil {
+		t.Fatalf("unexpected error deserializing spent outputs: %v", err)
+	}
+
+	if got[0].addrVersion != unversioned {
+		t.Fatalf("expected absent version to decode as sentinel %d, got %d", unversioned, got[0].addrVersion)
+	}
+	if got[0].addrVersion == 0 {
+		t.Fatal("absent version must never decode as the zero value, it is a valid version")
+	}
+}
+
+func TestRevertMilestoneResolvesUnversionedEntriesFromLiveSet(t *testing.T) {
+	spent := []SpentOutput{{Amount: 5, Address: nil}}
+	spent[0].TransactionID[0] = 0xcc
+
+	data := SerializeSpentOutputs(spent)
+	got, err := DeserializeSpentOutputs(data)
+	if err != nil {
+		t.Fatalf("unexpected error deserializing spent outputs: %v", err)
+	}
+
+	liveAddr := &iota.Ed25519Address{0x09}
+	var funded []SpentOutput
+
+	resolve := func(transactionID [iota.TransactionIDLength]byte, index uint16) (iota.Address, error) {
+		return liveAddr, nil
+	}
+	fund := func(s SpentOutput) error {
+		funded = append(funded, s)
+		return nil
+	}
+
+	if err := RevertMilestone(got, resolve, fund); err != nil {
+		t.Fatalf("unexpected error reverting unversioned entry: %v", err)
+	}
+	if len(funded) != 1 || funded[0].Address != iota.Address(liveAddr) {
+		t.Fatalf("expected the unversioned entry to be funded with the resolved live address")
+	}
+}
+
+func TestRevertMilestoneFailsOnUnversionedEntryWithoutResolver(t *testing.T) {
+	spent := []SpentOutput{{Address: nil}}
+	spent[0].TransactionID[0] = 0xdd
+
+	data := SerializeSpentOutputs(spent)
+	got, err := DeserializeSpentOutputs(data)
+	if err != nil {
+		t.Fatalf("unexpected error deserializing spent outputs: %v", err)
+	}
+
+	err = RevertMilestone(got, nil, func(SpentOutput) error { return nil })
+	if err == nil {
+		t.Fatal("expected an error reverting an unversioned entry without a live-set resolver")
+	}
+}
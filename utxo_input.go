@@ -54,15 +54,24 @@ func (u *UTXOInput) ID() UTXOInputID {
 	return id
 }
 
+// validateUTXOInputBytes runs the bounds/type checks shared by the batch (Deserialize) and
+// streaming (InputStreamDeserializer) decoding paths.
+func validateUTXOInputBytes(data []byte) error {
+	if err := checkMinByteLength(UTXOInputSize, len(data)); err != nil {
+		return fmt.Errorf("invalid UTXO input bytes: %w", err)
+	}
+	if err := checkTypeByte(data, InputUTXO); err != nil {
+		return fmt.Errorf("unable to deserialize UTXO input: %w", err)
+	}
+	return nil
+}
+
 func (u *UTXOInput) Deserialize(data []byte, deSeriMode DeSerializationMode) (int, error) {
 	return NewDeserializer(data).
 		AbortIf(func(err error) error {
 			if deSeriMode.HasMode(DeSeriModePerformValidation) {
-				if err := checkMinByteLength(UTXOInputSize, len(data)); err != nil {
-					return fmt.Errorf("invalid UTXO input bytes: %w", err)
-				}
-				if err := checkTypeByte(data, InputUTXO); err != nil {
-					return fmt.Errorf("unable to deserialize UTXO input: %w", err)
+				if err := validateUTXOInputBytes(data); err != nil {
+					return err
 				}
 			}
 			return nil